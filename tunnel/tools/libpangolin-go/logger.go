@@ -2,15 +2,33 @@ package main
 
 // #cgo LDFLAGS: -llog
 // #include <android/log.h>
+//
+// typedef void (*log_callback_t)(int level, const char* tag, const char* msg);
+//
+// static inline void call_log_callback(log_callback_t cb, int level, const char* tag, const char* msg) {
+//     if (cb) {
+//         cb(level, tag, msg);
+//     }
+// }
 import "C"
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 	"runtime/debug"
-	
+	"sync"
+	"sync/atomic"
+
 	"unsafe"
 
 	"github.com/fosrl/newt/logger"
@@ -21,6 +39,54 @@ import (
 var logFile *os.File
 var logFilePath string
 
+// logFileMu guards the logFile handle itself (open/close/rename) against
+// the log writer goroutine reading or rotating it concurrently.
+var logFileMu sync.Mutex
+
+// logChanCapacity bounds the async log pipeline; producers never block on
+// disk I/O, they just enqueue here for the background writer to drain.
+const logChanCapacity = 8192
+
+// logQueueItem is either a record to persist, or (when ack is set) a flush
+// barrier: the writer goroutine closes ack once everything queued ahead of
+// it has been written.
+type logQueueItem struct {
+	record logRecord
+	ack    chan struct{}
+}
+
+// logChanMu guards logChan/stopWriter/writerDone themselves (not what they
+// carry) against concurrent InitFileLogger/CloseFileLogger calls swapping
+// them out from under a producer. Readers (enqueueLog and FlushLogs, and
+// through enqueueLog evictLowPriority and fileSink.Write) run under RLock:
+// they only need a consistent
+// snapshot, and holding it for the duration of a channel send/receive
+// still lets start/stop wait for them via Lock rather than racing past.
+// startLogWriter/stopLogWriter take Lock only around the assignment
+// itself, not around <-writerDone, so a writer goroutine that calls back
+// into enqueueLog (flushDroppedCount, during its own shutdown drain) can
+// still take RLock without deadlocking against the Lock held here.
+var logChanMu sync.RWMutex
+
+var (
+	logChan          chan logQueueItem
+	stopWriter       chan struct{}
+	writerDone       chan struct{} // closed by logWriterLoop when this generation's goroutine returns
+	droppedLogs      int64         // atomic; dropped since the last flushDroppedCount, reset every ~5s
+	totalDroppedLogs int64         // atomic; cumulative dropped count, never reset - what GetLogStats reports
+)
+
+// recordDroppedLog counts one dropped record against both the
+// periodic-flush counter (which flushDroppedCount zeroes every ~5s) and
+// the cumulative counter GetLogStats reports. Without the split,
+// GetLogStats would almost always read back near-zero: by the time a user
+// checks it before filing a bug report, the periodic ticker has usually
+// already swapped droppedLogs back to 0.
+func recordDroppedLog() {
+	atomic.AddInt64(&droppedLogs, 1)
+	atomic.AddInt64(&totalDroppedLogs, 1)
+}
+
 // Log rotation settings
 const (
 	maxLogFileSize = 10 * 1024 * 1024 // 10 MB
@@ -49,18 +115,57 @@ func cstring(s string) *C.char {
 
 // Logger provides formatted logging functionality
 type Logger struct {
-	prefix   string
-	logLevel LogLevel
-	tag      *C.char
+	prefix     string
+	logLevel   LogLevel
+	androidTag string
+	fields     map[string]interface{}
 }
 
 // NewLogger creates a new logger instance
 func NewLogger(prefix string) *Logger {
 	return &Logger{
-		prefix:   prefix,
-		logLevel: LogLevelDebug,
-		tag:      cstring("GoBackend/" + prefix),
+		prefix:     prefix,
+		logLevel:   LogLevelDebug,
+		androidTag: "GoBackend/" + prefix,
+	}
+}
+
+// With returns a child logger that carries fields merged with the
+// parent's, attached to every record the child logs from then on.
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{
+		prefix:     l.prefix,
+		logLevel:   l.logLevel,
+		androidTag: l.androidTag,
+		fields:     merged,
+	}
+}
+
+// WithFields is a variadic convenience wrapper around With for call sites
+// that build up a key/value list in place rather than a map literal.
+func (l *Logger) WithFields(kv ...interface{}) *Logger {
+	return l.With(fieldsFromPairs(kv))
+}
+
+// fieldsFromPairs converts a flat key/value arg list, as accepted by the
+// *w logging methods, into a field map. A trailing unpaired key is dropped.
+func fieldsFromPairs(kv []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		fields[key] = kv[i+1]
 	}
+	return fields
 }
 
 // SetLevel sets the minimum log level
@@ -73,6 +178,60 @@ func (l *Logger) GetLevel() LogLevel {
 	return l.logLevel
 }
 
+// globalVerbosity is the klog-style V-level applied to loggers with no
+// per-module override, set via the setVerbosity export.
+var globalVerbosity int32 // atomic
+
+// moduleVerbosity holds per-tag V-level overrides set via
+// setModuleVerbosity, keyed by Logger.prefix (e.g. "wireguard-go").
+var moduleVerbosity sync.Map // map[string]int32
+
+// verbosity returns the effective V-level for l: its module override if
+// one was set, otherwise the global verbosity.
+func (l *Logger) verbosity() int32 {
+	if v, ok := moduleVerbosity.Load(l.prefix); ok {
+		return v.(int32)
+	}
+	return atomic.LoadInt32(&globalVerbosity)
+}
+
+// VLogger is a verbosity-gated view of a Logger, returned by Logger.V. Its
+// methods are no-ops - skipping even the fmt.Sprintf call - unless the
+// logger's effective verbosity is at least the level V was called with, so
+// packet-handling code can sprinkle l.V(3).Debug(...) calls for free when
+// that verbosity isn't enabled.
+type VLogger struct {
+	logger  *Logger
+	enabled bool
+}
+
+// V returns a logger that only logs if the currently configured verbosity
+// for l's tag is >= n.
+func (l *Logger) V(n int) VLogger {
+	return VLogger{logger: l, enabled: int32(n) <= l.verbosity()}
+}
+
+// Debug logs a debug message if this V-level is enabled.
+func (v VLogger) Debug(format string, args ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	v.logger.Debug(format, args...)
+}
+
+// Info logs an info message if this V-level is enabled.
+func (v VLogger) Info(format string, args ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	v.logger.Info(format, args...)
+}
+
+// Enabled reports whether this V-level would currently log anything.
+func (v VLogger) Enabled() bool {
+	return v.enabled
+}
+
 // formatMessage formats a log message with format string and args
 func (l *Logger) formatMessage(format string, args ...interface{}) string {
 	if len(args) > 0 {
@@ -81,47 +240,727 @@ func (l *Logger) formatMessage(format string, args ...interface{}) string {
 	return format
 }
 
-// logToAndroid sends a log message to Android logcat AND file
-func (l *Logger) logToAndroid(level LogLevel, format string, args ...interface{}) {
-	if l.logLevel > level {
-		return
-	}
-
-	message := l.formatMessage(format, args...)
-
-	// Map Go log levels to Android log levels
-	var androidLogLevel C.int
-	var levelStr string
+// androidLevel maps a LogLevel to its Android log level and display string.
+func androidLevel(level LogLevel) (C.int, string) {
 	switch level {
 	case LogLevelDebug:
-		androidLogLevel = C.ANDROID_LOG_DEBUG
-		levelStr = "DEBUG"
+		return C.ANDROID_LOG_DEBUG, "DEBUG"
 	case LogLevelInfo:
-		androidLogLevel = C.ANDROID_LOG_INFO
-		levelStr = "INFO"
+		return C.ANDROID_LOG_INFO, "INFO"
 	case LogLevelWarn:
-		androidLogLevel = C.ANDROID_LOG_WARN
-		levelStr = "WARN"
+		return C.ANDROID_LOG_WARN, "WARN"
 	case LogLevelError:
-		androidLogLevel = C.ANDROID_LOG_ERROR
-		levelStr = "ERROR"
+		return C.ANDROID_LOG_ERROR, "ERROR"
 	default:
-		androidLogLevel = C.ANDROID_LOG_INFO
-		levelStr = "INFO"
+		return C.ANDROID_LOG_INFO, "INFO"
 	}
+}
 
-	// Log to logcat
-	C.__android_log_write(androidLogLevel, l.tag, cstring(message))
-	
-	// Log to file
-	if logFile != nil {
-		timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-		logLine := fmt.Sprintf("%s [%s] %s: %s\n", timestamp, levelStr, l.prefix, message)
-		logFile.WriteString(logLine)
-		
-		// Check if we need to rotate the log
-		checkAndRotateLog()
+// logRecord is the JSON shape written to the log file, one object per line.
+type logRecord struct {
+	Ts     string                 `json:"ts"`
+	Level  string                 `json:"level"`
+	Tag    string                 `json:"tag"`
+	Msg    string                 `json:"msg"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Sink is a pluggable log output. Each sink filters independently so, for
+// example, the socket sink can run at DEBUG while the file sink stays at
+// INFO.
+type Sink interface {
+	// Write delivers one record to the sink, which drops it if level is
+	// below the sink's own minimum level.
+	Write(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{})
+	// SetLevel changes the sink's minimum level.
+	SetLevel(level LogLevel)
+}
+
+// sinkRegistry fans a record out to every registered sink.
+type sinkRegistry struct {
+	mu    sync.RWMutex
+	sinks map[string]Sink
+}
+
+func newSinkRegistry() *sinkRegistry {
+	return &sinkRegistry{sinks: make(map[string]Sink)}
+}
+
+func (r *sinkRegistry) add(id string, sink Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks[id] = sink
+}
+
+func (r *sinkRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sinks, id)
+}
+
+func (r *sinkRegistry) setLevel(id string, level LogLevel) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if sink, ok := r.sinks[id]; ok {
+		sink.SetLevel(level)
+	}
+}
+
+func (r *sinkRegistry) dispatch(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{}) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, sink := range r.sinks {
+		sink.Write(level, ts, tag, msg, fields)
+	}
+}
+
+// sinks is the process-wide registry every Logger writes through. Built-in
+// sinks for logcat and the rotated log file are registered in init().
+var sinks = newSinkRegistry()
+
+const (
+	logcatSinkID   = "logcat"
+	fileSinkID     = "file"
+	socketSinkID   = "socket"
+	callbackSinkID = "callback"
+)
+
+// logcatSink writes the human-readable line to Android logcat; this is
+// the sink form of what Logger.logToAndroid always did before sinks
+// existed.
+type logcatSink struct {
+	minLevel int32 // atomic, holds a LogLevel
+}
+
+func newLogcatSink(level LogLevel) *logcatSink {
+	s := &logcatSink{}
+	s.SetLevel(level)
+	return s
+}
+
+func (s *logcatSink) SetLevel(level LogLevel) {
+	atomic.StoreInt32(&s.minLevel, int32(level))
+}
+
+func (s *logcatSink) Write(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{}) {
+	if level < LogLevel(atomic.LoadInt32(&s.minLevel)) {
+		return
+	}
+	androidLogLevel, _ := androidLevel(level)
+	C.__android_log_write(androidLogLevel, cstring(tag), cstring(msg))
+}
+
+// fileSink hands records to the bounded async writer (enqueueLog /
+// logWriterLoop) that owns the rotated log file.
+type fileSink struct {
+	minLevel int32 // atomic, holds a LogLevel
+}
+
+func newFileSink(level LogLevel) *fileSink {
+	s := &fileSink{}
+	s.SetLevel(level)
+	return s
+}
+
+func (s *fileSink) SetLevel(level LogLevel) {
+	atomic.StoreInt32(&s.minLevel, int32(level))
+}
+
+func (s *fileSink) Write(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{}) {
+	if level < LogLevel(atomic.LoadInt32(&s.minLevel)) {
+		return
+	}
+	// enqueueLog itself checks whether a writer is running, under
+	// logChanMu; no need to duplicate an unsynchronized read of logChan
+	// here too.
+	_, levelStr := androidLevel(level)
+	enqueueLog(logRecord{
+		Ts:     ts.Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:  levelStr,
+		Tag:    tag,
+		Msg:    msg,
+		Fields: fields,
+	}, level)
+}
+
+// sinkQueueCapacity bounds the async per-connection dispatch queue used by
+// socketSink and callbackSink. Both used to write synchronously inline in
+// Logger.write's caller - including the WireGuard/OLM data path chunk0-2
+// specifically moved off of blocking I/O - so a stalled `adb forward`
+// client or a slow JNI callback could block that hot path for up to the
+// socket write deadline. Queuing here and delivering from a dedicated
+// goroutine keeps Write non-blocking for the producer, the same guarantee
+// fileSink gets from logChan/enqueueLog.
+const sinkQueueCapacity = 2048
+
+// sinkQueueItem is one record queued for an async sink's delivery
+// goroutine.
+type sinkQueueItem struct {
+	level  LogLevel
+	ts     time.Time
+	tag    string
+	msg    string
+	fields map[string]interface{}
+}
+
+// socketSink streams JSON log lines to connected TCP/Unix socket clients,
+// e.g. over `adb forward` for live tailing from a laptop.
+type socketSink struct {
+	minLevel int32 // atomic, holds a LogLevel
+
+	mu    sync.Mutex
+	ln    net.Listener
+	conns map[net.Conn]struct{}
+
+	queue chan sinkQueueItem
+	done  chan struct{}
+}
+
+func newSocketSink(network, address string, level LogLevel) (*socketSink, error) {
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	s := &socketSink{
+		ln:    ln,
+		conns: make(map[net.Conn]struct{}),
+		queue: make(chan sinkQueueItem, sinkQueueCapacity),
+		done:  make(chan struct{}),
+	}
+	s.SetLevel(level)
+	go s.acceptLoop()
+	go s.deliverLoop()
+	return s, nil
+}
+
+func (s *socketSink) acceptLoop() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+	}
+}
+
+// deliverLoop is the only goroutine that ever writes to connections, so a
+// slow or stalled client only ever blocks this loop - never a producer
+// calling Write - for up to the per-connection write deadline below.
+func (s *socketSink) deliverLoop() {
+	for {
+		select {
+		case item := <-s.queue:
+			s.deliver(item)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *socketSink) deliver(item sinkQueueItem) {
+	_, levelStr := androidLevel(item.level)
+	line, err := json.Marshal(logRecord{
+		Ts:     item.ts.Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:  levelStr,
+		Tag:    item.tag,
+		Msg:    item.msg,
+		Fields: item.fields,
+	})
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Write(line); err != nil {
+			conn.Close()
+			delete(s.conns, conn)
+		}
+	}
+}
+
+func (s *socketSink) SetLevel(level LogLevel) {
+	atomic.StoreInt32(&s.minLevel, int32(level))
+}
+
+// Write never blocks: it hands the record to deliverLoop over a bounded
+// queue and drops it if that queue is full, rather than stalling the
+// caller on a slow client's socket write.
+func (s *socketSink) Write(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{}) {
+	if level < LogLevel(atomic.LoadInt32(&s.minLevel)) {
+		return
+	}
+	select {
+	case s.queue <- sinkQueueItem{level: level, ts: ts, tag: tag, msg: msg, fields: fields}:
+	default:
+	}
+}
+
+func (s *socketSink) Close() {
+	close(s.done)
+	s.ln.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
 	}
+	s.conns = nil
+}
+
+// callbackSink invokes a JNI-registered C callback per record so Kotlin
+// can render logs inside an in-app debug console. Like socketSink, the
+// actual callback invocation happens on a dedicated goroutine so a slow
+// JNI callback can't block the producer.
+type callbackSink struct {
+	minLevel int32 // atomic, holds a LogLevel
+	cb       C.log_callback_t
+
+	queue chan sinkQueueItem
+	done  chan struct{}
+}
+
+func newCallbackSink(cb C.log_callback_t, level LogLevel) *callbackSink {
+	s := &callbackSink{cb: cb, queue: make(chan sinkQueueItem, sinkQueueCapacity), done: make(chan struct{})}
+	s.SetLevel(level)
+	go s.deliverLoop()
+	return s
+}
+
+func (s *callbackSink) deliverLoop() {
+	for {
+		select {
+		case item := <-s.queue:
+			C.call_log_callback(s.cb, C.int(item.level), cstring(item.tag), cstring(item.msg))
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *callbackSink) SetLevel(level LogLevel) {
+	atomic.StoreInt32(&s.minLevel, int32(level))
+}
+
+// Write never blocks: it hands the record to deliverLoop over a bounded
+// queue and drops it if that queue is full, rather than stalling the
+// caller on a slow JNI callback.
+func (s *callbackSink) Write(level LogLevel, ts time.Time, tag, msg string, fields map[string]interface{}) {
+	if level < LogLevel(atomic.LoadInt32(&s.minLevel)) {
+		return
+	}
+	select {
+	case s.queue <- sinkQueueItem{level: level, tag: tag, msg: msg}:
+	default:
+	}
+}
+
+func (s *callbackSink) Close() {
+	close(s.done)
+}
+
+// activeSinkMu guards activeSocketSink/activeCallbackSink against
+// concurrent AddSocketSink/RegisterLogCallback (or Remove/Unregister)
+// calls racing on the pointer itself and on Close() of the sink being
+// replaced.
+var activeSinkMu sync.Mutex
+
+var (
+	activeSocketSink   *socketSink
+	activeCallbackSink *callbackSink
+)
+
+// AddSink registers sink under id, replacing any sink already registered
+// there. Plain Go functions rather than //export entries, deliberately:
+// Sink is an interface and can't cross the cgo boundary, so Kotlin can't
+// call these directly. What it does get is a concrete //export wrapper per
+// built-in sink - AddSocketSink/RemoveSocketSink and
+// RegisterLogCallback/UnregisterLogCallback below - which is a narrower
+// surface than "expose sink registration" but is what's actually usable
+// from the JNI side.
+func AddSink(id string, sink Sink) {
+	sinks.add(id, sink)
+}
+
+// RemoveSink unregisters the sink previously registered under id, if any.
+// See AddSink for why this isn't a //export entry either.
+func RemoveSink(id string) {
+	sinks.remove(id)
+}
+
+// AddSocketSink starts a TCP or Unix socket listener (network is "tcp" or
+// "unix") that streams JSON log lines to every connected client, for
+// `adb forward`-style live tailing from a laptop. Returns 0 on success, -1
+// if the listener could not be started.
+//
+//export AddSocketSink
+func AddSocketSink(network *C.char, address *C.char, minLevel C.int) C.int {
+	sink, err := newSocketSink(C.GoString(network), C.GoString(address), LogLevel(minLevel))
+	if err != nil {
+		appLogger.Error("Failed to start socket log sink: %v", err)
+		return -1
+	}
+
+	activeSinkMu.Lock()
+	prev := activeSocketSink
+	activeSocketSink = sink
+	activeSinkMu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+	AddSink(socketSinkID, sink)
+	return 0
+}
+
+// RemoveSocketSink stops and unregisters the socket log sink, if active.
+//
+//export RemoveSocketSink
+func RemoveSocketSink() {
+	RemoveSink(socketSinkID)
+
+	activeSinkMu.Lock()
+	prev := activeSocketSink
+	activeSocketSink = nil
+	activeSinkMu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+}
+
+// RegisterLogCallback registers a C callback invoked once per log record
+// at or above minLevel, so Kotlin can render logs inside an in-app debug
+// console.
+//
+//export RegisterLogCallback
+func RegisterLogCallback(cb C.log_callback_t, minLevel C.int) {
+	sink := newCallbackSink(cb, LogLevel(minLevel))
+
+	activeSinkMu.Lock()
+	prev := activeCallbackSink
+	activeCallbackSink = sink
+	activeSinkMu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+	AddSink(callbackSinkID, sink)
+}
+
+// UnregisterLogCallback removes a previously registered log callback.
+//
+//export UnregisterLogCallback
+func UnregisterLogCallback() {
+	RemoveSink(callbackSinkID)
+
+	activeSinkMu.Lock()
+	prev := activeCallbackSink
+	activeCallbackSink = nil
+	activeSinkMu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+}
+
+// write fans message out to every registered sink (logcat and the rotated
+// file by default, plus whatever optional sinks are active).
+func (l *Logger) write(level LogLevel, message string, fields map[string]interface{}) {
+	sinks.dispatch(level, time.Now(), l.androidTag, message, fields)
+}
+
+// enqueueLog queues record for the writer goroutine without ever blocking.
+// On overflow, an incoming Debug/Info record is simply dropped and counted
+// instead of evicting anything, since they're the ones we can most afford
+// to lose. An incoming Warn/Error record first evicts a queued Debug/Info
+// record (see evictLowPriority) and only falls back to dropping the oldest
+// queued entry when that finds none.
+//
+// Takes logChanMu.RLock for its whole body: logChan is a package var
+// InitFileLogger/CloseFileLogger can replace or nil out concurrently from
+// any caller's goroutine, so every access to it here needs to be under the
+// same lock startLogWriter/stopLogWriter take to swap it.
+func enqueueLog(record logRecord, level LogLevel) {
+	logChanMu.RLock()
+	defer logChanMu.RUnlock()
+
+	if logChan == nil {
+		return
+	}
+
+	select {
+	case logChan <- logQueueItem{record: record}:
+		return
+	default:
+	}
+
+	if level <= LogLevelInfo {
+		recordDroppedLog()
+		return
+	}
+
+	// Warn/Error: the queue is full of higher-priority backlog too. Scan
+	// for a queued Debug/Info record to sacrifice instead of the head, so
+	// a burst of Warn/Error only evicts low-priority records and doesn't
+	// drop another Warn/Error unless none are queued.
+	if evictLowPriority() {
+		select {
+		case logChan <- logQueueItem{record: record}:
+			return
+		default:
+		}
+	}
+
+	// No Debug/Info victim found (or the slot it freed got taken again):
+	// fall back to evicting the oldest queued entry, which may itself be
+	// Warn/Error.
+	select {
+	case <-logChan:
+		recordDroppedLog()
+	default:
+	}
+	select {
+	case logChan <- logQueueItem{record: record}:
+	default:
+		recordDroppedLog()
+	}
+}
+
+// enqueueEvictScanLimit bounds how many queued items evictLowPriority
+// drains looking for a victim, so a queue full of nothing but Warn/Error
+// records doesn't cost a full-capacity scan on every overflow.
+const enqueueEvictScanLimit = 256
+
+// evictLowPriority drains up to enqueueEvictScanLimit items off the front
+// of logChan looking for a Debug/Info record to drop, requeuing everything
+// else it saw in its original order. Reports whether it evicted one.
+// Callers must already hold logChanMu (any variant - it only touches what
+// logChan carries, not the var itself) and have confirmed logChan != nil.
+func evictLowPriority() bool {
+	scan := enqueueEvictScanLimit
+	if c := cap(logChan); scan > c {
+		scan = c
+	}
+
+	var requeue []logQueueItem
+	evicted := false
+scanLoop:
+	for i := 0; i < scan; i++ {
+		select {
+		case item := <-logChan:
+			if !evicted && item.ack == nil && levelFromString(item.record.Level) <= LogLevelInfo {
+				evicted = true
+				recordDroppedLog()
+				continue
+			}
+			requeue = append(requeue, item)
+		default:
+			break scanLoop
+		}
+	}
+
+	for _, item := range requeue {
+		select {
+		case logChan <- item:
+		default:
+			// Queue filled back up while we were requeuing; drop and count
+			// rather than block.
+			recordDroppedLog()
+		}
+	}
+	return evicted
+}
+
+// startLogWriter spins up the background goroutine that owns all file
+// writes and rotation, so producers and CloseFileLogger never race on
+// logFile. If a writer from a previous InitFileLogger call is still
+// running (e.g. InitFileLogger called again without an intervening
+// CloseFileLogger across a VPN reconnect), it is stopped and joined first
+// so logChan/stopWriter/writerDone never get replaced out from under a
+// still-running goroutine.
+func startLogWriter() {
+	stopLogWriter()
+
+	ch := make(chan logQueueItem, logChanCapacity)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	logChanMu.Lock()
+	logChan = ch
+	stopWriter = stop
+	writerDone = done
+	logChanMu.Unlock()
+
+	go logWriterLoop(ch, stop, done)
+}
+
+// logWriterLoop drains ch until stop is closed, then flushes whatever is
+// left so no records are lost at VPN shutdown. done is this generation's
+// own completion signal - closing it (rather than a shared sync.WaitGroup)
+// means stopLogWriter can wait for exactly the generation it just
+// signalled, not whichever generation happens to finish next.
+func logWriterLoop(ch chan logQueueItem, stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	flushTicker := time.NewTicker(5 * time.Second)
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case item := <-ch:
+			handleLogQueueItem(item)
+		case <-flushTicker.C:
+			flushDroppedCount()
+		case <-stop:
+			// Drain, then flush the dropped-count record - which the file
+			// sink re-enqueues onto ch rather than writing synchronously -
+			// and keep draining until that settles, so the final flush
+			// isn't left stranded in the channel after this goroutine
+			// returns.
+			for {
+				select {
+				case item := <-ch:
+					handleLogQueueItem(item)
+					continue
+				default:
+				}
+				if atomic.LoadInt64(&droppedLogs) == 0 {
+					return
+				}
+				flushDroppedCount()
+			}
+		}
+	}
+}
+
+func handleLogQueueItem(item logQueueItem) {
+	if item.ack != nil {
+		close(item.ack)
+		return
+	}
+	writeLogRecord(item.record)
+}
+
+// writeLogRecord appends record to the log file and rotates if needed.
+// Only the writer goroutine calls this, so file access needs no locking
+// against itself - logFileMu only guards against InitFileLogger/
+// CloseFileLogger swapping logFile out from under it.
+func writeLogRecord(record logRecord) {
+	logFileMu.Lock()
+	defer logFileMu.Unlock()
+
+	if logFile == nil {
+		return
+	}
+	if line, err := json.Marshal(record); err == nil {
+		logFile.Write(append(line, '\n'))
+	}
+	checkAndRotateLog()
+}
+
+// flushDroppedCount reports how many records were dropped since the last
+// flush, if any, as a synthetic Warn record through the normal sink
+// registry - the same path every other record takes, so it respects each
+// sink's own level filter and reaches the socket/callback sinks too,
+// instead of always hitting logcat regardless of its configured minimum.
+func flushDroppedCount() {
+	n := atomic.SwapInt64(&droppedLogs, 0)
+	if n == 0 {
+		return
+	}
+	msg := fmt.Sprintf("%d log lines dropped", n)
+	sinks.dispatch(LogLevelWarn, time.Now(), "Logger", msg, nil)
+}
+
+// FlushLogs blocks until every record queued ahead of this call has been
+// written to disk. Useful before reading the log file (e.g. for export).
+//
+// Defensive by construction rather than by luck: it takes logChanMu.RLock
+// for its whole body, so CloseFileLogger's stopLogWriter (which takes
+// Lock to swap logChan/stopWriter) can't nil logChan out from under this
+// call between the nil check and the send. If the queue happens to be
+// full it gives up rather than blocking indefinitely on a channel a
+// concurrent CloseFileLogger might abandon - e.g. a bug-report "export
+// logs" tap landing right as the VPN service tears down.
+func FlushLogs() {
+	logChanMu.RLock()
+	defer logChanMu.RUnlock()
+
+	if logChan == nil {
+		return
+	}
+	ack := make(chan struct{})
+	select {
+	case logChan <- logQueueItem{ack: ack}:
+	default:
+		return
+	}
+	<-ack
+}
+
+// stopLogWriter signals the writer goroutine to drain and exit, and waits
+// for it to finish. Safe to call when no writer is running, and safe to
+// call concurrently with itself - only one caller ever proceeds past the
+// claim below.
+//
+// Waits on the done channel captured alongside stop, not a shared
+// sync.WaitGroup: a WaitGroup's counter can't tell generations apart, so
+// if startLogWriter raced in and re-Add'd before this call's Wait actually
+// observed zero, Wait would block until that newer generation also
+// stopped - which may be never. Reading this generation's own done channel
+// only ever waits for the goroutine stop (captured in the same lock) was
+// sent to.
+//
+// Deliberately does not hold logChanMu across <-done: the writer's own
+// shutdown drain calls flushDroppedCount, which can go through
+// fileSink.Write -> enqueueLog and take logChanMu.RLock. Holding Lock here
+// across the wait would deadlock against that RLock.
+func stopLogWriter() {
+	logChanMu.Lock()
+	stop := stopWriter
+	done := writerDone
+	stopWriter = nil // claim: any concurrent caller now sees nil and returns
+	writerDone = nil
+	logChanMu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+
+	logChanMu.Lock()
+	logChan = nil
+	logChanMu.Unlock()
+}
+
+// logToAndroid hands a record to every registered sink. l.logLevel is
+// deliberately not checked here: each sink applies its own level filter
+// (see Sink), and gating here first would clamp every sink to whichever
+// level is lowest across all of them, defeating the point of per-sink
+// filtering. l.logLevel/SetLevel/GetLevel still exist for reporting the
+// configured level elsewhere (e.g. GetLogLevelString for OLM config); use
+// setLogLevel/SetFileSinkLevel/SetLogcatSinkLevel/AddSocketSink's minLevel
+// to actually control what reaches a given destination.
+func (l *Logger) logToAndroid(level LogLevel, format string, args ...interface{}) {
+	l.write(level, l.formatMessage(format, args...), l.fields)
+}
+
+// logw logs msg at level with l's fields merged with the trailing key/value
+// pairs in kv, used by the Debugw/Infow/Warnw/Errorw variants. See
+// logToAndroid for why l.logLevel isn't checked here either.
+func (l *Logger) logw(level LogLevel, msg string, kv ...interface{}) {
+	fields := l.fields
+	if len(kv) > 0 {
+		fields = l.With(fieldsFromPairs(kv)).fields
+	}
+	l.write(level, msg, fields)
 }
 
 // Debug logs a debug message
@@ -144,12 +983,38 @@ func (l *Logger) Error(format string, args ...interface{}) {
 	l.logToAndroid(LogLevelError, format, args...)
 }
 
+// Debugw logs a debug message with trailing key/value fields.
+func (l *Logger) Debugw(msg string, kv ...interface{}) {
+	l.logw(LogLevelDebug, msg, kv...)
+}
+
+// Infow logs an info message with trailing key/value fields.
+func (l *Logger) Infow(msg string, kv ...interface{}) {
+	l.logw(LogLevelInfo, msg, kv...)
+}
+
+// Warnw logs a warning message with trailing key/value fields.
+func (l *Logger) Warnw(msg string, kv ...interface{}) {
+	l.logw(LogLevelWarn, msg, kv...)
+}
+
+// Errorw logs an error message with trailing key/value fields.
+func (l *Logger) Errorw(msg string, kv ...interface{}) {
+	l.logw(LogLevelError, msg, kv...)
+}
+
 // AndroidLogWriter adapts our Logger to the newt/logger LogWriter interface
 type AndroidLogWriter struct {
 	logger *Logger
 }
 
-// Write implements the logger.LogWriter interface
+// Write implements the logger.LogWriter interface. Upstream newt/logger's
+// LogWriter interface only carries a level, timestamp and formatted
+// message - no field map - so there is nothing here to preserve onto
+// w.logger's own fields; the JSON file output's "fields" key is simply
+// absent for records that arrive through this adapter. If newt/logger ever
+// grows a structured variant of LogWriter, wire its fields through here
+// rather than dropping them.
 func (w *AndroidLogWriter) Write(level logger.LogLevel, timestamp time.Time, message string) {
 	// Map newt/logger.LogLevel to our LogLevel
 	var ourLevel LogLevel
@@ -184,10 +1049,24 @@ func NewAndroidLogWriter(logger *Logger) *AndroidLogWriter {
 	return &AndroidLogWriter{logger: logger}
 }
 
+// V exposes the wrapped Logger's verbosity gate, so newt/logger callers
+// holding an AndroidLogWriter directly (not just the logger.LogWriter
+// interface it satisfies) get cheap V-gated debug sites too.
+func (w *AndroidLogWriter) V(n int) VLogger {
+	return w.logger.V(n)
+}
+
 // global logger instance
 var appLogger *Logger
 
 func init() {
+	// Register the built-in sinks before anything logs. Both default to
+	// DEBUG; setLogLevel moves both together to whatever level a caller
+	// configures, and SetFileSinkLevel/SetLogcatSinkLevel let a caller
+	// split them apart afterwards.
+	sinks.add(logcatSinkID, newLogcatSink(LogLevelDebug))
+	sinks.add(fileSinkID, newFileSink(LogLevelDebug))
+
 	appLogger = NewLogger("PangolinGo")
 	// Log level will be set via setLogLevel
 	appLogger.Info("Logger initialized")
@@ -216,17 +1095,26 @@ func init() {
 //export InitFileLogger
 func InitFileLogger(filePath *C.char) {
 	goPath := C.GoString(filePath)
+
+	// logFilePath is read under logFileMu elsewhere (logFileSet,
+	// checkAndRotateLog), so the write needs the same lock.
+	logFileMu.Lock()
 	logFilePath = goPath
-	
+	logFileMu.Unlock()
+
 	// Clean up old backup logs on initialization
 	cleanupOldBackups()
-	
+
+	logFileMu.Lock()
 	var err error
 	logFile, err = os.OpenFile(goPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFileMu.Unlock()
 	if err != nil {
 		appLogger.Error("Failed to open log file: %v", err)
 		return
 	}
+
+	startLogWriter()
 	appLogger.Info("File logging initialized: %s", goPath)
 }
 
@@ -234,6 +1122,12 @@ func InitFileLogger(filePath *C.char) {
 //
 //export CloseFileLogger
 func CloseFileLogger() {
+	// Stop and join the writer goroutine first so nothing is still writing
+	// to logFile when we close it below.
+	stopLogWriter()
+
+	logFileMu.Lock()
+	defer logFileMu.Unlock()
 	if logFile != nil {
 		logFile.Close()
 		logFile = nil
@@ -302,12 +1196,402 @@ func cleanupOldBackups() {
 	}
 }
 
-// setLogLevel sets the log level for the Go logger
+// secretPatterns are redacted from exported/tailed log lines before they
+// leave the device, since they may be attached to a support email. Each
+// has a plaintext form ("PrivateKey = ...") and a JSON form
+// ("privateKey":"...") since log lines are JSON but fields can themselves
+// carry plaintext-formatted values (e.g. a forwarded config dump).
+// Plaintext value groups stop at the first quote, comma, closing brace or
+// whitespace, and JSON value groups at the closing quote, so redaction
+// never swallows the fields or line content that follow the secret.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(private[_ ]?key\s*[:=]\s*)([^\s",}]+)`),
+	regexp.MustCompile(`(?i)("[^"]*private[_ ]?key"\s*:\s*")([^"]*)`),
+	regexp.MustCompile(`(?i)(Authorization\s*:\s*Bearer\s+)([^\s",}]+)`),
+	regexp.MustCompile(`(?i)("Authorization"\s*:\s*"Bearer\s+)([^"]*)`),
+}
+
+// redactSecrets replaces recognized secret values in line with [REDACTED],
+// keeping the surrounding key/label so the export stays readable.
+func redactSecrets(line string) string {
+	for _, re := range secretPatterns {
+		line = re.ReplaceAllString(line, "${1}[REDACTED]")
+	}
+	return line
+}
+
+// logFileSet returns the current log file plus whatever rotated backups
+// (.1, .2, ...) still exist, in newest-first order.
+func logFileSet() []string {
+	if logFilePath == "" {
+		return nil
+	}
+	files := []string{logFilePath}
+	for i := 1; i <= maxLogBackups; i++ {
+		name := fmt.Sprintf("%s.%d", logFilePath, i)
+		if _, err := os.Stat(name); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// logLine is one parsed record from the log file, kept alongside its raw
+// JSON text so export/tail can filter without re-marshaling.
+type logLine struct {
+	ts    time.Time
+	level LogLevel
+	raw   string
+}
+
+func levelFromString(s string) LogLevel {
+	switch s {
+	case "DEBUG":
+		return LogLevelDebug
+	case "WARN":
+		return LogLevelWarn
+	case "ERROR":
+		return LogLevelError
+	default:
+		return LogLevelInfo
+	}
+}
+
+// parseLogLine parses one JSON log record, reporting false for a line that
+// doesn't parse (e.g. a stray partial write).
+func parseLogLine(raw string) (logLine, bool) {
+	var rec logRecord
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return logLine{}, false
+	}
+	ts, err := time.Parse("2006-01-02T15:04:05.000Z07:00", rec.Ts)
+	if err != nil {
+		return logLine{}, false
+	}
+	return logLine{ts: ts, level: levelFromString(rec.Level), raw: raw}, true
+}
+
+// tailChunkSize is the block size readLogLinesBackward reads from the end
+// of a log file at a time.
+const tailChunkSize = 64 * 1024
+
+// readLogLinesBackward scans path from the end in tailChunkSize blocks,
+// parsing records newest-first, without loading the whole file into
+// memory the way a forward scanner would - a plain
+// bufio.Scanner-over-the-whole-file approach means TailLogs(50) always
+// pays for every rotated byte on disk even though it only needs the last
+// 50 lines. It stops once it has collected limit matching records (limit
+// <= 0 means no count bound - keep going until since or the start of the
+// file stops it), or once it reaches a record older than since (since
+// zero means no lower bound). Returned lines are oldest-first, matching
+// every other line slice in this file. hitSince reports whether a record
+// older than since was seen, so a caller walking rotated files
+// newest-to-oldest can stop opening older ones once it's true - they're
+// chronologically before since too.
+func readLogLinesBackward(path string, limit int, minLevel LogLevel, since time.Time) (lines []logLine, hitSince bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var collected []logLine // newest-first while scanning; reversed before returning
+	var leftover []byte     // an incomplete line, carried back to be completed by the next (earlier) chunk
+
+	buf := make([]byte, tailChunkSize)
+	pos := info.Size()
+	for pos > 0 {
+		if (limit > 0 && len(collected) >= limit) || hitSince {
+			break
+		}
+
+		readSize := int64(len(buf))
+		if readSize > pos {
+			readSize = pos
+		}
+		pos -= readSize
+		if _, err := f.ReadAt(buf[:readSize], pos); err != nil && err != io.EOF {
+			return nil, false, err
+		}
+
+		chunk := append(append([]byte{}, buf[:readSize]...), leftover...)
+		parts := bytes.Split(chunk, []byte("\n"))
+
+		start := 0
+		if pos > 0 {
+			// parts[0] is the earliest data in this chunk; we don't yet
+			// know whether a newline precedes it further back in the
+			// file, so hold it and complete it on the next iteration.
+			leftover = parts[0]
+			start = 1
+		} else {
+			leftover = nil
+		}
+
+		for i := len(parts) - 1; i >= start; i-- {
+			raw := strings.TrimRight(string(parts[i]), "\r")
+			if raw == "" {
+				continue
+			}
+			l, ok := parseLogLine(raw)
+			if !ok {
+				continue
+			}
+			if !since.IsZero() && l.ts.Before(since) {
+				hitSince = true
+				break
+			}
+			if l.level < minLevel {
+				continue
+			}
+			collected = append(collected, l)
+			if limit > 0 && len(collected) >= limit {
+				break
+			}
+		}
+	}
+
+	for i, j := 0, len(collected)-1; i < j; i, j = i+1, j-1 {
+		collected[i], collected[j] = collected[j], collected[i]
+	}
+	return collected, hitSince, nil
+}
+
+// tailLogLines returns the last limit records across the rotated log set
+// (limit <= 0 means all of them), oldest-first. Walks files newest-first
+// and stops opening older ones as soon as enough records have been
+// collected.
+func tailLogLines(limit int) []logLine {
+	FlushLogs()
+
+	logFileMu.Lock()
+	files := logFileSet() // newest-first
+	logFileMu.Unlock()
+
+	var perFile [][]logLine
+	remaining := limit
+	for _, path := range files {
+		fileLimit := 0
+		if limit > 0 {
+			fileLimit = remaining
+		}
+		lines, _, err := readLogLinesBackward(path, fileLimit, LogLevelDebug, time.Time{})
+		if err != nil {
+			appLogger.Warn("Error scanning log file %s: %v", path, err)
+			continue
+		}
+		perFile = append(perFile, lines)
+		if limit > 0 {
+			remaining -= len(lines)
+			if remaining <= 0 {
+				break
+			}
+		}
+	}
+
+	var all []logLine
+	for i := len(perFile) - 1; i >= 0; i-- {
+		all = append(all, perFile[i]...)
+	}
+	if limit > 0 && len(all) > limit {
+		all = all[len(all)-limit:]
+	}
+	return all
+}
+
+// exportLogLines merges every record from the rotated log set that is at
+// or above minLevel and no older than sinceUnixMs (0 means no lower
+// bound), sorted oldest first. Stops opening older rotated files once a
+// newer one's backward scan reaches sinceUnixMs, instead of always
+// reading and parsing the full rotated set.
+func exportLogLines(sinceUnixMs int64, minLevel LogLevel) []logLine {
+	FlushLogs()
+
+	logFileMu.Lock()
+	files := logFileSet() // newest-first
+	logFileMu.Unlock()
+
+	var since time.Time
+	if sinceUnixMs > 0 {
+		since = time.UnixMilli(sinceUnixMs)
+	}
+
+	var perFile [][]logLine
+	for _, path := range files {
+		lines, hitSince, err := readLogLinesBackward(path, 0, minLevel, since)
+		if err != nil {
+			appLogger.Warn("Error scanning log file %s: %v", path, err)
+			continue
+		}
+		perFile = append(perFile, lines)
+		if hitSince {
+			break
+		}
+	}
+
+	var all []logLine
+	for i := len(perFile) - 1; i >= 0; i-- {
+		all = append(all, perFile[i]...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ts.Before(all[j].ts) })
+	return all
+}
+
+// ExportLogs merges the rotated log set in timestamp order, applying
+// sinceUnixMs/minLevel filters and secret redaction, and writes the result
+// to a temp file (optionally gzip-compressed) whose path is returned for
+// Kotlin to attach to a support email or share intent. Returns an empty
+// string on failure.
+//
+// The returned pointer is C-owned (allocated with C.CString, unlike the
+// transient cstring helper used elsewhere in this file for pointers that
+// never outlive the call) because Kotlin reads it after this call returns;
+// the caller must free it with C.free once done, e.g. via JNA/JNI's normal
+// native-string release path.
+//
+//export ExportLogs
+func ExportLogs(sinceUnixMs C.longlong, minLevel C.int, gzipOut C.int) *C.char {
+	lines := exportLogLines(int64(sinceUnixMs), LogLevel(minLevel))
+
+	pattern := "pangolin-logs-*.log"
+	if gzipOut != 0 {
+		pattern = "pangolin-logs-*.log.gz"
+	}
+	out, err := os.CreateTemp("", pattern)
+	if err != nil {
+		appLogger.Error("Failed to create log export file: %v", err)
+		return C.CString("")
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	var gz *gzip.Writer
+	if gzipOut != 0 {
+		gz = gzip.NewWriter(out)
+		w = gz
+	}
+
+	for _, l := range lines {
+		fmt.Fprintln(w, redactSecrets(l.raw))
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			appLogger.Error("Failed to finalize gzip log export: %v", err)
+			return C.CString("")
+		}
+	}
+
+	return C.CString(out.Name())
+}
+
+// TailLogs returns the last n lines of the rotated log set, newest last,
+// redacted the same way ExportLogs redacts its output.
+//
+// Like ExportLogs, the returned pointer is allocated with C.CString and
+// must be freed by the caller with C.free once read.
+//
+//export TailLogs
+func TailLogs(n C.int) *C.char {
+	lines := tailLogLines(int(n))
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(redactSecrets(l.raw))
+		b.WriteByte('\n')
+	}
+	return C.CString(b.String())
+}
+
+// logStats is the JSON payload returned by GetLogStats.
+type logStats struct {
+	Files      int   `json:"files"`
+	TotalBytes int64 `json:"totalBytes"`
+	Dropped    int64 `json:"dropped"`
+}
+
+// GetLogStats reports the size of the current rotated log set and the
+// cumulative count of records dropped by the async writer, as JSON.
+// Dropped reads totalDroppedLogs rather than the droppedLogs counter
+// flushDroppedCount swaps back to 0 every ~5s - reading the latter here
+// would almost always report ~0, since a user checking stats before
+// filing a bug report virtually never lands inside that 5s window.
+//
+// Like ExportLogs, the returned pointer is allocated with C.CString and
+// must be freed by the caller with C.free once parsed.
+//
+//export GetLogStats
+func GetLogStats() *C.char {
+	logFileMu.Lock()
+	files := logFileSet()
+	logFileMu.Unlock()
+
+	stats := logStats{Files: len(files), Dropped: atomic.LoadInt64(&totalDroppedLogs)}
+	for _, path := range files {
+		if info, err := os.Stat(path); err == nil {
+			stats.TotalBytes += info.Size()
+		}
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return C.CString("{}")
+	}
+	return C.CString(string(data))
+}
+
+// setLogLevel sets the log level for the Go logger, and moves the two
+// built-in sinks (logcat and file) to the same level, since most callers
+// only ever touch this one knob and expect it to govern everything they
+// see. Call SetFileSinkLevel/SetLogcatSinkLevel afterwards to split one of
+// them off to its own level, e.g. to keep the on-disk log at INFO while a
+// socket or callback sink added at DEBUG captures everything for a live
+// debugging session.
 // level: 0=DEBUG, 1=INFO, 2=WARN, 3=ERROR
 //
 //export setLogLevel
 func setLogLevel(level C.int) {
 	appLogger.SetLevel(LogLevel(level))
+	sinks.setLevel(fileSinkID, LogLevel(level))
+	sinks.setLevel(logcatSinkID, LogLevel(level))
+}
+
+// SetFileSinkLevel overrides the built-in file sink's minimum level
+// independently of setLogLevel.
+//
+//export SetFileSinkLevel
+func SetFileSinkLevel(level C.int) {
+	sinks.setLevel(fileSinkID, LogLevel(level))
+}
+
+// SetLogcatSinkLevel overrides the built-in logcat sink's minimum level
+// independently of setLogLevel.
+//
+//export SetLogcatSinkLevel
+func SetLogcatSinkLevel(level C.int) {
+	sinks.setLevel(logcatSinkID, LogLevel(level))
+}
+
+// setVerbosity sets the default V-level applied to any logger that has no
+// per-module override, e.g. for l.V(3).Debug(...) call sites.
+//
+//export setVerbosity
+func setVerbosity(v C.int) {
+	atomic.StoreInt32(&globalVerbosity, int32(v))
+}
+
+// setModuleVerbosity overrides the V-level for a single logger tag (e.g.
+// "wireguard-go") without affecting the global verbosity, so a user
+// chasing a handshake bug can bump just that module's V-level.
+//
+//export setModuleVerbosity
+func setModuleVerbosity(tag *C.char, v C.int) {
+	moduleVerbosity.Store(C.GoString(tag), int32(v))
 }
 
 // getCurrentLogLevel returns the current log level from appLogger
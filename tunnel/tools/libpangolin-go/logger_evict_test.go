@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+// drainLogChan empties logChan without blocking, for asserting on what
+// evictLowPriority left behind.
+func drainLogChan() []logQueueItem {
+	var items []logQueueItem
+	for {
+		select {
+		case item := <-logChan:
+			items = append(items, item)
+		default:
+			return items
+		}
+	}
+}
+
+func TestEvictLowPriorityDropsDebugInfoBeforeWarnError(t *testing.T) {
+	orig := logChan
+	defer func() { logChan = orig }()
+
+	logChan = make(chan logQueueItem, 4)
+	logChan <- logQueueItem{record: logRecord{Level: "WARN", Msg: "w1"}}
+	logChan <- logQueueItem{record: logRecord{Level: "DEBUG", Msg: "d1"}}
+	logChan <- logQueueItem{record: logRecord{Level: "ERROR", Msg: "e1"}}
+
+	if !evictLowPriority() {
+		t.Fatal("expected evictLowPriority to find a Debug/Info victim")
+	}
+
+	remaining := drainLogChan()
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 items left after eviction, got %d", len(remaining))
+	}
+	for _, item := range remaining {
+		if item.record.Msg == "d1" {
+			t.Fatalf("evicted item should have been the Debug record, but it's still queued: %+v", remaining)
+		}
+	}
+}
+
+func TestEvictLowPriorityFalseWhenNoLowPriorityQueued(t *testing.T) {
+	orig := logChan
+	defer func() { logChan = orig }()
+
+	logChan = make(chan logQueueItem, 2)
+	logChan <- logQueueItem{record: logRecord{Level: "WARN", Msg: "w1"}}
+	logChan <- logQueueItem{record: logRecord{Level: "ERROR", Msg: "e1"}}
+
+	if evictLowPriority() {
+		t.Fatal("expected evictLowPriority to report no victim when the queue holds only Warn/Error")
+	}
+
+	remaining := drainLogChan()
+	if len(remaining) != 2 {
+		t.Fatalf("expected both items still queued when nothing was evicted, got %d", len(remaining))
+	}
+}
+
+func TestEvictLowPriorityLeavesAckBarriersQueued(t *testing.T) {
+	orig := logChan
+	defer func() { logChan = orig }()
+
+	logChan = make(chan logQueueItem, 3)
+	ack := make(chan struct{})
+	logChan <- logQueueItem{ack: ack}
+	logChan <- logQueueItem{record: logRecord{Level: "DEBUG", Msg: "d1"}}
+	logChan <- logQueueItem{record: logRecord{Level: "WARN", Msg: "w1"}}
+
+	if !evictLowPriority() {
+		t.Fatal("expected evictLowPriority to find the Debug record")
+	}
+
+	remaining := drainLogChan()
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 items left after eviction, got %d", len(remaining))
+	}
+	sawAck := false
+	for _, item := range remaining {
+		if item.ack != nil {
+			sawAck = true
+		}
+		if item.record.Msg == "d1" {
+			t.Fatalf("evicted item should have been the Debug record, but it's still queued: %+v", remaining)
+		}
+	}
+	if !sawAck {
+		t.Fatal("expected the flush-barrier item to survive eviction, never a candidate for it")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsFromPairs(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   []interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "pairs",
+			kv:   []interface{}{"peer", "abc123", "bytes", 42},
+			want: map[string]interface{}{"peer": "abc123", "bytes": 42},
+		},
+		{
+			name: "trailing unpaired key dropped",
+			kv:   []interface{}{"peer", "abc123", "orphan"},
+			want: map[string]interface{}{"peer": "abc123"},
+		},
+		{
+			name: "non-string key skipped",
+			kv:   []interface{}{42, "not a key", "peer", "abc123"},
+			want: map[string]interface{}{"peer": "abc123"},
+		},
+		{
+			name: "empty",
+			kv:   nil,
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldsFromPairs(tt.kv)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldsFromPairs(%v) = %v, want %v", tt.kv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelFromString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want LogLevel
+	}{
+		{"DEBUG", LogLevelDebug},
+		{"INFO", LogLevelInfo},
+		{"WARN", LogLevelWarn},
+		{"ERROR", LogLevelError},
+		{"", LogLevelInfo},
+		{"bogus", LogLevelInfo},
+	}
+
+	for _, tt := range tests {
+		if got := levelFromString(tt.s); got != tt.want {
+			t.Errorf("levelFromString(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "plaintext private key",
+			line: "PrivateKey = aGVsbG8gd29ybGQ= other=stuff",
+			want: "PrivateKey = [REDACTED] other=stuff",
+		},
+		{
+			name: "json private key",
+			line: `{"tag":"wireguard-go","privateKey":"aGVsbG8gd29ybGQ=","peer":"1"}`,
+			want: `{"tag":"wireguard-go","privateKey":"[REDACTED]","peer":"1"}`,
+		},
+		{
+			name: "plaintext bearer token",
+			line: "Authorization: Bearer sk-abc.def-123 remainder",
+			want: "Authorization: Bearer [REDACTED] remainder",
+		},
+		{
+			name: "json bearer token",
+			line: `{"Authorization":"Bearer sk-abc.def-123","path":"/api"}`,
+			want: `{"Authorization":"Bearer [REDACTED]","path":"/api"}`,
+		},
+		{
+			name: "no secret present",
+			line: `{"tag":"tunnel","msg":"handshake complete"}`,
+			want: `{"tag":"tunnel","msg":"handshake complete"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactSecrets(tt.line); got != tt.want {
+				t.Errorf("redactSecrets(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}